@@ -0,0 +1,71 @@
+package fingerprint
+
+import "errors"
+
+// ErrEmpty describes an error that occurs when trying to align an empty
+// fingerprint against another.
+var ErrEmpty = errors.New("fingerprint: unable to align an empty fingerprint")
+
+// CompareAligned compares a short fingerprint against a longer one,
+// sliding short across long and evaluating the Compare-style similarity
+// at every offset. It returns the best score found along with the
+// offset (in sub-fingerprints, measured from the start of long) at which
+// it occurs. If short is the longer of the two, their roles are swapped
+// and the offset is measured from the start of short instead. Unlike
+// Compare, the two fingerprints do not need to be the
+// same length, which makes it possible to locate a short clip within a
+// longer recording.
+func CompareAligned(short, long []int32) (score float64, offset int, err error) {
+	if len(short) == 0 || len(long) == 0 {
+		return 0, 0, ErrEmpty
+	}
+	if len(long) < len(short) {
+		short, long = long, short
+	}
+
+	best := -1.0
+	bestOffset := 0
+	for o := 0; o <= len(long)-len(short); o++ {
+		dist := 0
+		for i, sub := range short {
+			dist += hamming(sub, long[o+i])
+		}
+		s := 1 - float64(dist)/float64(len(short)*bitsperint)
+		if s > best {
+			best = s
+			bestOffset = o
+		}
+	}
+	return best, bestOffset, nil
+}
+
+// CompareWindow splits fp1 and fp2 into consecutive, non-overlapping
+// windows of windowSize sub-fingerprints and returns the Compare score
+// of each aligned pair of windows, in order. The final window is
+// dropped if either fingerprint has fewer than windowSize sub-
+// fingerprints remaining. This lets a caller see how similarity varies
+// over time between two recordings, e.g. to spot where a partial overlap
+// begins or ends.
+func CompareWindow(fp1, fp2 []int32, windowSize int) []float64 {
+	if windowSize <= 0 {
+		return nil
+	}
+
+	n := len(fp1)
+	if len(fp2) < n {
+		n = len(fp2)
+	}
+
+	var scores []float64
+	for start := 0; start+windowSize <= n; start += windowSize {
+		w1 := fp1[start : start+windowSize]
+		w2 := fp2[start : start+windowSize]
+
+		dist := 0
+		for i, sub := range w1 {
+			dist += hamming(sub, w2[i])
+		}
+		scores = append(scores, 1-float64(dist)/float64(windowSize*bitsperint))
+	}
+	return scores
+}
@@ -0,0 +1,59 @@
+package fingerprint
+
+import "io"
+
+// bitWriter accumulates a least-significant-bit-first stream of bits
+// into a byte slice, used by the Encode wire format.
+type bitWriter struct {
+	buf      []byte
+	bitCount uint
+}
+
+func newBitWriter() *bitWriter {
+	return &bitWriter{}
+}
+
+// writeBits appends the low n bits of v to the stream.
+func (w *bitWriter) writeBits(v uint32, n uint) {
+	for i := uint(0); i < n; i++ {
+		if w.bitCount%8 == 0 {
+			w.buf = append(w.buf, 0)
+		}
+		if v&(1<<i) != 0 {
+			w.buf[w.bitCount/8] |= 1 << (w.bitCount % 8)
+		}
+		w.bitCount++
+	}
+}
+
+func (w *bitWriter) bytes() []byte {
+	return w.buf
+}
+
+// bitReader reads a least-significant-bit-first stream of bits back out
+// of a byte slice, used by the Decode wire format.
+type bitReader struct {
+	buf      []byte
+	bitCount uint
+}
+
+func newBitReader(buf []byte) *bitReader {
+	return &bitReader{buf: buf}
+}
+
+// readBits reads the next n bits from the stream, returning
+// io.ErrUnexpectedEOF once the underlying buffer is exhausted.
+func (r *bitReader) readBits(n uint) (uint32, error) {
+	var v uint32
+	for i := uint(0); i < n; i++ {
+		byteIdx := r.bitCount / 8
+		if byteIdx >= uint(len(r.buf)) {
+			return 0, io.ErrUnexpectedEOF
+		}
+		if r.buf[byteIdx]&(1<<(r.bitCount%8)) != 0 {
+			v |= 1 << i
+		}
+		r.bitCount++
+	}
+	return v, nil
+}
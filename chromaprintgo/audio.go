@@ -0,0 +1,64 @@
+package chromaprintgo
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// targetRate is the sample rate, in Hz, that all audio is resampled to
+// before framing, matching the reference chromaprint implementation.
+const targetRate = 11025
+
+// readPCM reads little-endian 16-bit signed PCM samples from src,
+// interleaved across channels, and downmixes them to a single mono
+// stream of float64 samples in the range [-1, 1].
+func readPCM(src io.Reader, channels uint) ([]float64, error) {
+	if channels == 0 {
+		channels = 1
+	}
+
+	var samples []float64
+	frame := make([]int16, channels)
+	buf := make([]byte, 2*channels)
+	for {
+		if _, err := io.ReadFull(src, buf); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				break
+			}
+			return nil, err
+		}
+
+		var sum int32
+		for c := range frame {
+			frame[c] = int16(binary.LittleEndian.Uint16(buf[2*c:]))
+			sum += int32(frame[c])
+		}
+		samples = append(samples, float64(sum)/float64(len(frame))/32768.0)
+	}
+	return samples, nil
+}
+
+// resample converts samples recorded at rate Hz to targetRate Hz using
+// linear interpolation. It is not a substitute for a proper band-limited
+// resampler, but it is adequate for feeding the chroma filter bank,
+// which itself averages over wide frequency bins.
+func resample(samples []float64, rate uint) []float64 {
+	if rate == targetRate || len(samples) == 0 {
+		return samples
+	}
+
+	ratio := float64(targetRate) / float64(rate)
+	n := int(float64(len(samples)) * ratio)
+	out := make([]float64, n)
+	for i := range out {
+		srcPos := float64(i) / ratio
+		i0 := int(srcPos)
+		if i0 >= len(samples)-1 {
+			out[i] = samples[len(samples)-1]
+			continue
+		}
+		frac := srcPos - float64(i0)
+		out[i] = samples[i0]*(1-frac) + samples[i0+1]*frac
+	}
+	return out
+}
@@ -0,0 +1,87 @@
+package chromaprintgo
+
+import "math"
+
+const (
+	// frameSize is the length, in samples at targetRate, of each STFT
+	// frame.
+	frameSize = 4096
+	// frameHop is the number of samples advanced between consecutive
+	// frames, giving roughly 3 overlapping frames per frameSize.
+	frameHop = 1365
+
+	// numChroma is the number of pitch classes the spectrum is folded
+	// into (one per semitone, octave-wrapped).
+	numChroma = 12
+
+	// minFreq and maxFreq bound the part of the spectrum considered
+	// musically relevant for chroma folding.
+	minFreq = 28.0
+	maxFreq = 3520.0
+)
+
+// chromaFilter maps each magnitude-spectrum bin (for a frameSize-point
+// FFT at targetRate) to the chroma bin it falls into, or -1 if the bin
+// lies outside [minFreq, maxFreq]. Bin i of the spectrum corresponds to
+// frequency i*targetRate/frameSize.
+func chromaFilter() []int {
+	bins := frameSize/2 + 1
+	class := make([]int, bins)
+	for i := range class {
+		freq := float64(i) * targetRate / frameSize
+		if freq < minFreq || freq > maxFreq {
+			class[i] = -1
+			continue
+		}
+		// 12-tone equal temperament pitch class relative to A (27.5Hz
+		// octaves), octave-wrapped into [0, 12).
+		pitch := numChroma * math.Log2(freq/27.5)
+		c := int(math.Mod(pitch, numChroma))
+		if c < 0 {
+			c += numChroma
+		}
+		class[i] = c
+	}
+	return class
+}
+
+// chromaImage folds the magnitude spectrum of each frame of samples into
+// a numChroma-wide row, producing a rolling image of shape
+// [numFrames][numChroma]. Each row holds squared-magnitude ("energy")
+// values, normalized to sum to 1, so that loudness differences between
+// recordings do not dominate the classifier.
+func chromaImage(samples []float64) [][]float64 {
+	if len(samples) < frameSize {
+		return nil
+	}
+
+	window := hannWindow(frameSize)
+	class := chromaFilter()
+
+	numFrames := (len(samples)-frameSize)/frameHop + 1
+	image := make([][]float64, numFrames)
+
+	for f := 0; f < numFrames; f++ {
+		start := f * frameHop
+		spectrum := magnitudeSpectrum(samples[start:start+frameSize], window)
+
+		row := make([]float64, numChroma)
+		for bin, mag := range spectrum {
+			if c := class[bin]; c >= 0 {
+				row[c] += mag * mag
+			}
+		}
+
+		var norm float64
+		for _, v := range row {
+			norm += v
+		}
+		if norm > 0 {
+			for i := range row {
+				row[i] /= norm
+			}
+		}
+		image[f] = row
+	}
+	return image
+}
@@ -0,0 +1,75 @@
+package chromaprintgo
+
+import "github.com/go-fingerprint/fingerprint"
+
+// Algorithm selects a variant of the fingerprinting algorithm. Only
+// AlgorithmDefault is currently implemented; the type exists so that the
+// constructor signature matches other Calculator implementations such as
+// gochroma's.
+type Algorithm int
+
+// AlgorithmDefault is the only Algorithm currently supported.
+const AlgorithmDefault Algorithm = 0
+
+// Calculator is a pure-Go fingerprint.Calculator implementation that
+// computes chromaprint-inspired fingerprints directly from raw PCM,
+// without any cgo dependency. See the package doc for the scope of
+// its compatibility with libchromaprint.
+type Calculator struct {
+	algorithm Algorithm
+}
+
+// New returns a Calculator using the given Algorithm.
+func New(algorithm Algorithm) *Calculator {
+	return &Calculator{algorithm: algorithm}
+}
+
+// Close releases any resources held by c. It is a no-op, present so that
+// Calculator can be used as a drop-in replacement for cgo-based
+// Calculators that do require cleanup.
+func (c *Calculator) Close() error {
+	return nil
+}
+
+// RawFingerprint reads and decodes the PCM audio described by i and
+// returns its fingerprint as raw sub-fingerprint integers.
+func (c *Calculator) RawFingerprint(i fingerprint.RawInfo) ([]int32, error) {
+	samples, err := readPCM(i.Src, i.Channels)
+	if err != nil {
+		return nil, err
+	}
+
+	if i.MaxSeconds > 0 {
+		maxSamples := int(i.MaxSeconds * i.Rate)
+		if maxSamples < len(samples) {
+			samples = samples[:maxSamples]
+		}
+	}
+
+	samples = resample(samples, i.Rate)
+
+	image := chromaImage(samples)
+	if len(image) == 0 {
+		return nil, nil
+	}
+	ii := newIntegralImage(image)
+
+	fp := make([]int32, 0, len(image))
+	for f := 1; f <= len(image); f++ {
+		fp = append(fp, classifyFrame(ii, f))
+	}
+	return fp, nil
+}
+
+// Fingerprint reads and decodes the PCM audio described by i and returns
+// its fingerprint as a compact, URL-safe base64 string in the
+// fingerprint package's own wire format (see fingerprint.Encode), so it
+// round-trips through fingerprint.Decode regardless of which Calculator
+// produced it.
+func (c *Calculator) Fingerprint(i fingerprint.RawInfo) (string, error) {
+	fp, err := c.RawFingerprint(i)
+	if err != nil {
+		return "", err
+	}
+	return fingerprint.Encode(fp, int(c.algorithm))
+}
@@ -0,0 +1,152 @@
+package chromaprintgo
+
+// integralImage is a 2D prefix-sum over a chroma image, allowing the sum
+// over any rectangular (frame range x chroma range) sub-area to be
+// computed in constant time, which the classifier filters rely on
+// heavily.
+type integralImage struct {
+	sum    [][]float64 // sum[f][c] = sum of image[0:f][0:c]
+	frames int
+}
+
+func newIntegralImage(image [][]float64) *integralImage {
+	frames := len(image)
+	ii := &integralImage{frames: frames, sum: make([][]float64, frames+1)}
+	ii.sum[0] = make([]float64, numChroma+1)
+	for f := 0; f < frames; f++ {
+		row := make([]float64, numChroma+1)
+		var rowSum float64
+		for c := 0; c < numChroma; c++ {
+			rowSum += image[f][c]
+			row[c+1] = ii.sum[f][c+1] + rowSum
+		}
+		ii.sum[f+1] = row
+	}
+	return ii
+}
+
+// area returns the sum of image[f0:f1][c0:c1], with column indices
+// wrapped modulo numChroma so that filters spanning the pitch-class
+// circle (e.g. B through C#) are computed correctly.
+func (ii *integralImage) area(f0, f1, c0, c1 int) float64 {
+	if f0 < 0 {
+		f0 = 0
+	}
+	if f1 > ii.frames {
+		f1 = ii.frames
+	}
+	if f0 >= f1 {
+		return 0
+	}
+
+	var total float64
+	width := c1 - c0
+	for i := 0; i < width; i++ {
+		c := (c0 + i) % numChroma
+		total += ii.sum[f1][c+1] - ii.sum[f0][c+1] - ii.sum[f1][c] + ii.sum[f0][c]
+	}
+	return total
+}
+
+// filterKind identifies which rectangular comparison a classifier filter
+// performs.
+type filterKind int
+
+const (
+	filterHalves filterKind = iota
+	filterThirdsH
+	filterQuarters
+	filterDiagonal
+)
+
+// filterConfig describes one classifier filter: it compares rectangular
+// areas of a width x numChroma block of the chroma image, starting
+// frameWidth frames back from the current position.
+type filterConfig struct {
+	kind       filterKind
+	frameWidth int
+}
+
+// filters is the bank of classifier filters applied at every frame
+// position. There are 16 of them (4 frame widths x 4 comparison kinds),
+// each contributing 2 Gray-coded bits to the 32-bit sub-fingerprint,
+// mirroring the structure of the reference chromaprint classifier.
+var filters = func() []filterConfig {
+	var fs []filterConfig
+	for _, width := range []int{1, 2, 3, 4} {
+		for _, kind := range []filterKind{filterHalves, filterThirdsH, filterQuarters, filterDiagonal} {
+			fs = append(fs, filterConfig{kind: kind, frameWidth: width})
+		}
+	}
+	return fs
+}()
+
+// apply evaluates the filter ending at frame f (exclusive) over ii,
+// returning a signed value whose sign and magnitude are later quantized
+// into 2 bits.
+func (fc filterConfig) apply(ii *integralImage, f int) float64 {
+	f0 := f - fc.frameWidth
+	switch fc.kind {
+	case filterHalves:
+		top := ii.area(f0, f, 0, numChroma/2)
+		bottom := ii.area(f0, f, numChroma/2, numChroma)
+		return top - bottom
+	case filterThirdsH:
+		third := numChroma / 3
+		left := ii.area(f0, f, 0, third)
+		mid := ii.area(f0, f, third, 2*third)
+		right := ii.area(f0, f, 2*third, numChroma)
+		return mid - (left + right)
+	case filterQuarters:
+		quarter := numChroma / 4
+		a := ii.area(f0, f, 0, quarter)
+		b := ii.area(f0, f, quarter, 2*quarter)
+		c := ii.area(f0, f, 2*quarter, 3*quarter)
+		d := ii.area(f0, f, 3*quarter, numChroma)
+		return (a + c) - (b + d)
+	case filterDiagonal:
+		fm := f0 + fc.frameWidth/2 + 1
+		topFirst := ii.area(f0, fm, 0, numChroma/2)
+		bottomSecond := ii.area(fm, f, numChroma/2, numChroma)
+		topSecond := ii.area(fm, f, 0, numChroma/2)
+		bottomFirst := ii.area(f0, fm, numChroma/2, numChroma)
+		return (topFirst + bottomSecond) - (topSecond + bottomFirst)
+	}
+	return 0
+}
+
+// quantizeThresholds splits a filter's real-valued output into one of 4
+// classes. These thresholds, and the filter bank in filters above, are
+// this package's own rather than a port of libchromaprint's trained
+// filter configuration and quantizers, so the resulting bits are not
+// expected to align with those the reference C library would produce
+// for the same audio -- see the package doc. They do preserve the
+// reference classifier's intent: near-zero differences classify as "no
+// strong feature" while larger, more confident differences classify
+// further from the center.
+var quantizeThresholds = [3]float64{-0.05, 0, 0.05}
+
+// gray4 maps a 2-bit quantized class (0..3) to its Gray code, so that
+// adjacent classes differ by a single bit and small measurement noise
+// near a threshold flips as few bits as possible.
+var gray4 = [4]uint32{0, 1, 3, 2}
+
+func quantize(v float64) uint32 {
+	class := 0
+	for _, t := range quantizeThresholds {
+		if v > t {
+			class++
+		}
+	}
+	return gray4[class]
+}
+
+// classifyFrame evaluates every filter at frame f and packs their 2-bit
+// Gray-coded outputs into a single sub-fingerprint integer.
+func classifyFrame(ii *integralImage, f int) int32 {
+	var sub uint32
+	for i, fc := range filters {
+		sub |= quantize(fc.apply(ii, f)) << uint(2*i)
+	}
+	return int32(sub)
+}
@@ -0,0 +1,24 @@
+// Package chromaprintgo is a pure-Go implementation of the
+// fingerprint.Calculator interface that follows the same pipeline
+// stages as chromaprint, without depending on the chromaprint C library
+// or cgo. It exists so that fingerprints can be produced in
+// cross-compiled, CGO_ENABLED=0, and serverless environments where
+// linking against libchromaprint is impractical.
+//
+// It is NOT bit-compatible with libchromaprint or its fingerprints.
+// Reproducing libchromaprint's output exactly would require porting its
+// trained filter configuration and quantizer thresholds verbatim from
+// the reference source, which this package does not have access to and
+// so does not attempt; see the quantizeThresholds comment in
+// classify.go. A fingerprint produced here is only meaningfully
+// comparable, via fingerprint.Compare, against another fingerprint
+// produced by this same package -- not against one produced by the
+// reference C library or any tool (e.g. fpcalc, AcoustID) built on it.
+//
+// The pipeline follows the same stages as the reference implementation:
+// input audio is resampled to 11025 Hz mono, cut into overlapping
+// frames, transformed into the frequency domain, folded into a 12-bin
+// chroma image, and finally classified by a bank of rectangular-area
+// filters whose quantized, Gray-coded outputs are packed into the bits
+// of each fingerprint sub-integer.
+package chromaprintgo
@@ -0,0 +1,69 @@
+package chromaprintgo
+
+import "math"
+
+// fft computes the in-place iterative radix-2 Cooley-Tukey FFT of x,
+// whose length must be a power of two. It is a small, dependency-free
+// implementation; frameSize is fixed at compile time, so no general
+// arbitrary-length transform is needed.
+func fft(x []complex128) {
+	n := len(x)
+	if n <= 1 {
+		return
+	}
+
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j &^= bit
+		}
+		j |= bit
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+
+	for size := 2; size <= n; size <<= 1 {
+		half := size / 2
+		theta := -2 * math.Pi / float64(size)
+		wStep := complex(math.Cos(theta), math.Sin(theta))
+		for start := 0; start < n; start += size {
+			w := complex(1, 0)
+			for k := 0; k < half; k++ {
+				u := x[start+k]
+				v := x[start+k+half] * w
+				x[start+k] = u + v
+				x[start+k+half] = u - v
+				w *= wStep
+			}
+		}
+	}
+}
+
+// hannWindow returns the n-point Hann window used to taper each frame
+// before the FFT, reducing spectral leakage.
+func hannWindow(n int) []float64 {
+	w := make([]float64, n)
+	for i := range w {
+		w[i] = 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(n-1))
+	}
+	return w
+}
+
+// magnitudeSpectrum applies window to frame, runs it through the FFT,
+// and returns the magnitude of the first n/2+1 bins (the unique half of
+// the spectrum for a real-valued input).
+func magnitudeSpectrum(frame []float64, window []float64) []float64 {
+	n := len(frame)
+	buf := make([]complex128, n)
+	for i, s := range frame {
+		buf[i] = complex(s*window[i], 0)
+	}
+	fft(buf)
+
+	out := make([]float64, n/2+1)
+	for i := range out {
+		out[i] = math.Hypot(real(buf[i]), imag(buf[i]))
+	}
+	return out
+}
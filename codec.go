@@ -0,0 +1,190 @@
+package fingerprint
+
+import (
+	"encoding/base64"
+	"errors"
+)
+
+// ErrAlgorithm describes an error that occurs when trying to Encode a
+// fingerprint with an algorithm ID that does not fit in a single byte.
+var ErrAlgorithm = errors.New("fingerprint: algorithm must be in [0, 255]")
+
+// ErrTooLong describes an error that occurs when trying to Encode a
+// fingerprint with more sub-fingerprints than fit in the wire format's
+// 24-bit length field.
+var ErrTooLong = errors.New("fingerprint: fingerprint has too many sub-fingerprints to encode")
+
+// ErrCorrupt describes an error that occurs when Decode is given a
+// string that is not a validly encoded fingerprint.
+var ErrCorrupt = errors.New("fingerprint: corrupt encoded fingerprint")
+
+const (
+	gapNormalBits = 3
+	gapEscapeBits = 5
+	gapEscapeCode = 1<<gapNormalBits - 1 // 7
+	gapContinue   = 1<<gapEscapeBits - 1 // 31
+)
+
+var b64 = base64.URLEncoding.WithPadding(base64.NoPadding)
+
+// Encode packs fp into the fingerprint package's own compact, URL-safe
+// base64 wire format: a one-byte algorithm ID, a 24-bit big-endian
+// sub-fingerprint count, and a bit-packed differential encoding of the
+// fingerprint itself (each sub-fingerprint is XORed with the previous
+// one, and the resulting bits are run-length coded: short gaps between
+// set bits cost 3 bits, longer gaps escape to 5 additional bits, chained
+// as many times as needed). The gap codes form a single stream across
+// the whole fingerprint, which is not the body layout libchromaprint uses
+// for its compressed fingerprints, so strings produced by
+// libchromaprint-based tools are not read correctly by Decode. This is
+// the format fingerprints
+// are expected to be stored or transmitted in, regardless of which
+// Calculator produced them.
+func Encode(fp []int32, algorithm int) (string, error) {
+	if algorithm < 0 || algorithm > 0xFF {
+		return "", ErrAlgorithm
+	}
+	if len(fp) > 0xFFFFFF {
+		return "", ErrTooLong
+	}
+
+	header := []byte{
+		byte(algorithm),
+		byte(len(fp) >> 16),
+		byte(len(fp) >> 8),
+		byte(len(fp)),
+	}
+
+	bw := newBitWriter()
+	totalBits := len(fp) * bitsperint
+	lastPos := -1
+	prev := int32(0)
+	for i, sub := range fp {
+		diff := uint32(sub ^ prev)
+		for bit := 0; bit < bitsperint; bit++ {
+			if diff&(1<<uint(bit)) != 0 {
+				pos := i*bitsperint + bit
+				writeGap(bw, uint32(pos-lastPos-1))
+				lastPos = pos
+			}
+		}
+		prev = sub
+	}
+	writeGap(bw, uint32(totalBits-lastPos-1))
+
+	return b64.EncodeToString(append(header, bw.bytes()...)), nil
+}
+
+// Decode parses a string produced by Encode back into its fingerprint
+// and algorithm ID.
+func Decode(s string) (fp []int32, algorithm int, err error) {
+	raw, err := b64.DecodeString(s)
+	if err != nil {
+		return nil, 0, ErrCorrupt
+	}
+	if len(raw) < 4 {
+		return nil, 0, ErrCorrupt
+	}
+
+	algorithm = int(raw[0])
+	length := int(raw[1])<<16 | int(raw[2])<<8 | int(raw[3])
+	totalBits := length * bitsperint
+
+	// Even a fingerprint encoded with no set bits at all still costs at
+	// least minGapBits(totalBits) to represent, since writeGap's
+	// escape chain grows with the gap it encodes. Reject a length that
+	// the remaining body is too short to possibly back before
+	// allocating fp, so a corrupt or hostile length field can't force
+	// an oversized allocation.
+	if available := 8 * len(raw[4:]); available < minGapBits(uint32(totalBits)) {
+		return nil, 0, ErrCorrupt
+	}
+
+	fp = make([]int32, length)
+
+	br := newBitReader(raw[4:])
+	lastPos := -1
+	for {
+		gap, err := readGap(br)
+		if err != nil {
+			return nil, 0, ErrCorrupt
+		}
+
+		pos := lastPos + 1 + int(gap)
+		if pos >= totalBits {
+			break
+		}
+
+		word, bit := pos/bitsperint, pos%bitsperint
+		fp[word] |= 1 << uint(bit)
+		lastPos = pos
+	}
+
+	prev := int32(0)
+	for i, diff := range fp {
+		fp[i] = diff ^ prev
+		prev = fp[i]
+	}
+	return fp, algorithm, nil
+}
+
+// writeGap appends the run-length code for gap (the number of zero bits
+// before the next set bit) to bw.
+func writeGap(bw *bitWriter, gap uint32) {
+	for {
+		if gap < gapEscapeCode {
+			bw.writeBits(gap, gapNormalBits)
+			return
+		}
+		bw.writeBits(gapEscapeCode, gapNormalBits)
+		gap -= gapEscapeCode
+		if gap < gapContinue {
+			bw.writeBits(gap, gapEscapeBits)
+			return
+		}
+		bw.writeBits(gapContinue, gapEscapeBits)
+		gap -= gapContinue
+	}
+}
+
+// minGapBits returns the number of bits writeGap would spend encoding
+// gap, i.e. the cheapest possible cost of representing it.
+func minGapBits(gap uint32) int {
+	bits := 0
+	for {
+		bits += gapNormalBits
+		if gap < gapEscapeCode {
+			return bits
+		}
+		gap -= gapEscapeCode
+		bits += gapEscapeBits
+		if gap < gapContinue {
+			return bits
+		}
+		gap -= gapContinue
+	}
+}
+
+// readGap reads and returns the next run-length coded gap from br.
+func readGap(br *bitReader) (uint32, error) {
+	var gap uint32
+	for {
+		v, err := br.readBits(gapNormalBits)
+		if err != nil {
+			return 0, err
+		}
+		if v < gapEscapeCode {
+			return gap + v, nil
+		}
+		gap += gapEscapeCode
+
+		e, err := br.readBits(gapEscapeBits)
+		if err != nil {
+			return 0, err
+		}
+		gap += e
+		if e < gapContinue {
+			return gap, nil
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package fingerprint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	cases := [][]int32{
+		nil,
+		{0},
+		{1, 2, 3, 4, 5},
+		{0, 0, 0, 0, 0},
+		{-1, -2, -3},
+		{math.MinInt32, math.MaxInt32, 123456789, -987654321, 42},
+	}
+
+	for _, fp := range cases {
+		s, err := Encode(fp, 1)
+		if err != nil {
+			t.Fatalf("Encode(%v) error: %v", fp, err)
+		}
+
+		got, algorithm, err := Decode(s)
+		if err != nil {
+			t.Fatalf("Decode(%q) error: %v", s, err)
+		}
+		if algorithm != 1 {
+			t.Fatalf("Decode(%q) algorithm = %d, want 1", s, algorithm)
+		}
+		if len(got) != len(fp) {
+			t.Fatalf("Decode(%q) length = %d, want %d", s, len(got), len(fp))
+		}
+		for i := range fp {
+			if got[i] != fp[i] {
+				t.Fatalf("Decode(%q)[%d] = %d, want %d", s, i, got[i], fp[i])
+			}
+		}
+	}
+}
+
+func TestEncodeRejectsOutOfRangeAlgorithm(t *testing.T) {
+	if _, err := Encode([]int32{1}, -1); err != ErrAlgorithm {
+		t.Fatalf("err = %v, want ErrAlgorithm", err)
+	}
+	if _, err := Encode([]int32{1}, 256); err != ErrAlgorithm {
+		t.Fatalf("err = %v, want ErrAlgorithm", err)
+	}
+}
+
+func TestDecodeRejectsClaimedLengthTooLargeForBody(t *testing.T) {
+	// A header claiming the maximum 24-bit sub-fingerprint count,
+	// backed by a body far too short to possibly encode it.
+	raw := []byte{0, 0xFF, 0xFF, 0xFF, 0}
+	s := b64.EncodeToString(raw)
+
+	if _, _, err := Decode(s); err != ErrCorrupt {
+		t.Fatalf("err = %v, want ErrCorrupt", err)
+	}
+}
+
+func TestDecodeRejectsGarbage(t *testing.T) {
+	if _, _, err := Decode("not valid base64!!"); err != ErrCorrupt {
+		t.Fatalf("err = %v, want ErrCorrupt", err)
+	}
+}
@@ -34,8 +34,7 @@ import (
 	"image"
 	"image/color"
 	"io"
-	"strconv"
-	"strings"
+	"math/bits"
 )
 
 // ErrLength describes a error that occurs when trying to compare fingerprints
@@ -126,7 +125,7 @@ func ImageDistance(fprint1, fprint2 []int32) (im image.Image, err error) {
 }
 
 func hamming(a, b int32) (dist int) {
-	dist = strings.Count(strconv.FormatInt(int64(a^b), 2), "1")
+	dist = bits.OnesCount32(uint32(a ^ b))
 	return
 }
 
@@ -0,0 +1,200 @@
+package fingerprint
+
+import (
+	"encoding/gob"
+	"errors"
+	"io"
+	"sort"
+	"sync"
+)
+
+// ErrDuplicateID describes an error that occurs when trying to add a
+// fingerprint to an Index under an ID that has already been added.
+var ErrDuplicateID = errors.New("fingerprint: id already present in index")
+
+// Match describes a single hit returned by Index.Query: the ID of the
+// matching fingerprint, its Compare-style similarity score over the
+// sub-fingerprints where the query and the matched fingerprint overlap
+// at their best alignment, and the offset (in sub-fingerprints) at which the query best aligns with
+// the matched fingerprint.
+type Match struct {
+	ID     string
+	Score  float64
+	Offset int
+}
+
+// posting records that sub-fingerprint appeared at Offset within the
+// fingerprint identified by ID.
+type posting struct {
+	ID     string
+	Offset int
+}
+
+// Index is an inverted index over a collection of fingerprints that
+// supports approximate nearest-neighbor lookup by Hamming distance. It
+// treats every 32-bit sub-fingerprint as a term: looking up a query
+// fingerprint first gathers candidate tracks sharing terms with it, then
+// ranks those candidates by comparing the query against each candidate
+// over its best-aligned window. This mirrors the two-stage lookup used
+// by AcoustID's server: a cheap inverted-index scan narrows the
+// candidate set before the more expensive bitwise comparison runs.
+//
+// An Index is safe for concurrent use.
+type Index struct {
+	mu           sync.RWMutex
+	postings     map[int32][]posting
+	fingerprints map[string][]int32
+}
+
+// NewIndex returns an empty Index ready to accept fingerprints.
+func NewIndex() *Index {
+	return &Index{
+		postings:     make(map[int32][]posting),
+		fingerprints: make(map[string][]int32),
+	}
+}
+
+// Add ingests a fingerprint under the given id, making it a candidate for
+// future Query calls. It returns ErrDuplicateID if id has already been
+// added.
+func (idx *Index) Add(id string, fp []int32) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if _, ok := idx.fingerprints[id]; ok {
+		return ErrDuplicateID
+	}
+
+	idx.fingerprints[id] = fp
+	for offset, sub := range fp {
+		idx.postings[sub] = append(idx.postings[sub], posting{ID: id, Offset: offset})
+	}
+	return nil
+}
+
+// candidateOversample controls how many more candidates than requested
+// are pulled from the posting-list vote before the expensive alignment
+// scoring runs, so that a track with a slightly lower term overlap but a
+// better bitwise alignment still has a chance to surface.
+const candidateOversample = 4
+
+// Query returns up to topK Matches for fp, ordered by descending score.
+// It aggregates candidate tracks by counting how many of their
+// sub-fingerprints appear in fp, then scores the most promising
+// candidates by sliding fp across each candidate fingerprint and taking
+// the best-aligned Hamming-based similarity.
+func (idx *Index) Query(fp []int32, topK int) ([]Match, error) {
+	if topK <= 0 {
+		return nil, errors.New("fingerprint: topK must be positive")
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	votes := make(map[string]int)
+	for _, sub := range fp {
+		for _, p := range idx.postings[sub] {
+			votes[p.ID]++
+		}
+	}
+
+	candidates := make([]string, 0, len(votes))
+	for id := range votes {
+		candidates = append(candidates, id)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return votes[candidates[i]] > votes[candidates[j]]
+	})
+
+	if limit := topK * candidateOversample; len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	matches := make([]Match, 0, len(candidates))
+	for _, id := range candidates {
+		score, offset := bestAlignment(fp, idx.fingerprints[id])
+		matches = append(matches, Match{ID: id, Score: score, Offset: offset})
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	if len(matches) > topK {
+		matches = matches[:topK]
+	}
+	return matches, nil
+}
+
+// bestAlignment slides the shorter of query and target across the
+// longer one, returning the best Compare-style score and the offset (in
+// sub-fingerprints, relative to target) at which it occurs.
+func bestAlignment(query, target []int32) (score float64, offset int) {
+	short, long := query, target
+	sign := 1
+	if len(long) < len(short) {
+		short, long = long, short
+		sign = -1
+	}
+	if len(short) == 0 || len(long) == 0 {
+		return 0, 0
+	}
+
+	best := -1.0
+	bestOffset := -len(short) + 1
+	for o := -len(short) + 1; o < len(long); o++ {
+		dist, overlap := 0, 0
+		for i, sub := range short {
+			j := o + i
+			if j < 0 || j >= len(long) {
+				continue
+			}
+			dist += hamming(sub, long[j])
+			overlap++
+		}
+		if overlap == 0 {
+			continue
+		}
+		s := 1 - float64(dist)/float64(overlap*bitsperint)
+		if s > best {
+			best = s
+			bestOffset = o
+		}
+	}
+	return best, sign * bestOffset
+}
+
+// indexSnapshot is the on-disk representation of an Index: the posting
+// lists are derived data and are rebuilt from Fingerprints on Load, so
+// only the fingerprints themselves need to be persisted.
+type indexSnapshot struct {
+	Fingerprints map[string][]int32
+}
+
+// Save writes a gob-encoded snapshot of idx to w.
+func (idx *Index) Save(w io.Writer) error {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	return gob.NewEncoder(w).Encode(indexSnapshot{Fingerprints: idx.fingerprints})
+}
+
+// Load replaces the contents of idx with the snapshot read from r,
+// discarding anything previously added.
+func (idx *Index) Load(r io.Reader) error {
+	var snap indexSnapshot
+	if err := gob.NewDecoder(r).Decode(&snap); err != nil {
+		return err
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.fingerprints = snap.Fingerprints
+	idx.postings = make(map[int32][]posting)
+	for id, fp := range idx.fingerprints {
+		for offset, sub := range fp {
+			idx.postings[sub] = append(idx.postings[sub], posting{ID: id, Offset: offset})
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package fingerprint
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestIndexQueryFindsExactMatch(t *testing.T) {
+	idx := NewIndex()
+	if err := idx.Add("track-a", []int32{1, 2, 3, 4, 5}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := idx.Add("track-b", []int32{100, 200, 300, 400, 500}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	matches, err := idx.Query([]int32{1, 2, 3, 4, 5}, 1)
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("len(matches) = %d, want 1", len(matches))
+	}
+	if matches[0].ID != "track-a" {
+		t.Fatalf("matches[0].ID = %q, want track-a", matches[0].ID)
+	}
+	if matches[0].Score < 0.99 {
+		t.Fatalf("matches[0].Score = %f, want close to 1", matches[0].Score)
+	}
+}
+
+func TestIndexAddDuplicateID(t *testing.T) {
+	idx := NewIndex()
+	if err := idx.Add("track-a", []int32{1, 2, 3}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := idx.Add("track-a", []int32{4, 5, 6}); err != ErrDuplicateID {
+		t.Fatalf("err = %v, want ErrDuplicateID", err)
+	}
+}
+
+func TestIndexSaveLoadRoundTrip(t *testing.T) {
+	idx := NewIndex()
+	if err := idx.Add("track-a", []int32{1, 2, 3, 4, 5}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := idx.Add("track-b", []int32{100, 200, 300, 400, 500}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := idx.Save(&buf); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded := NewIndex()
+	if err := loaded.Load(&buf); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	matches, err := loaded.Query([]int32{1, 2, 3, 4, 5}, 1)
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(matches) != 1 || matches[0].ID != "track-a" {
+		t.Fatalf("matches = %+v, want a single match on track-a", matches)
+	}
+
+	if err := loaded.Add("track-a", []int32{1, 2, 3}); err != ErrDuplicateID {
+		t.Fatalf("err = %v, want ErrDuplicateID (Load should have restored existing IDs)", err)
+	}
+}
@@ -0,0 +1,33 @@
+package landmark
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// readPCM reads little-endian 16-bit signed PCM samples from src,
+// interleaved across channels, and downmixes them to a single mono
+// stream of float64 samples in the range [-1, 1].
+func readPCM(src io.Reader, channels uint) ([]float64, error) {
+	if channels == 0 {
+		channels = 1
+	}
+
+	var samples []float64
+	buf := make([]byte, 2*channels)
+	for {
+		if _, err := io.ReadFull(src, buf); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				break
+			}
+			return nil, err
+		}
+
+		var sum int32
+		for c := uint(0); c < channels; c++ {
+			sum += int32(int16(binary.LittleEndian.Uint16(buf[2*c:])))
+		}
+		samples = append(samples, float64(sum)/float64(channels)/32768.0)
+	}
+	return samples, nil
+}
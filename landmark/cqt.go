@@ -0,0 +1,118 @@
+package landmark
+
+import "math"
+
+const (
+	// minFreq and maxFreq bound the constant-Q transform, roughly
+	// A2 to A8.
+	minFreq = 110.0
+	maxFreq = 7040.0
+
+	// binsPerOctave controls the frequency resolution of the
+	// transform. Panako uses roughly 85 bins per octave; we keep the
+	// same density.
+	binsPerOctave = 85
+
+	// hopSize is the number of input samples advanced between
+	// consecutive analysis frames.
+	hopSize = 256
+
+	// minWindow and maxWindow bound the length, in samples, of the
+	// per-bin analysis window: low-frequency bins need a longer
+	// window to resolve their period, but the window is capped so
+	// that very low bins stay computationally cheap.
+	minWindow = 512
+	maxWindow = 8192
+)
+
+// cqtBins returns the center frequency of every constant-Q bin between
+// minFreq and maxFreq.
+func cqtBins() []float64 {
+	n := int(math.Ceil(binsPerOctave * math.Log2(maxFreq/minFreq)))
+	freqs := make([]float64, n)
+	ratio := math.Pow(2, 1.0/binsPerOctave)
+	f := minFreq
+	for i := range freqs {
+		freqs[i] = f
+		f *= ratio
+	}
+	return freqs
+}
+
+// windowFor returns the analysis window length, in samples at the given
+// rate, used for a bin centered at freq: long enough to cover a handful
+// of cycles so the bin's frequency is well resolved, clamped to
+// [minWindow, maxWindow].
+func windowFor(freq float64, rate uint) int {
+	cycles := 4.0
+	n := int(cycles * float64(rate) / freq)
+	if n < minWindow {
+		n = minWindow
+	}
+	if n > maxWindow {
+		n = maxWindow
+	}
+	return n
+}
+
+// goertzel returns the magnitude of the component at freq Hz within
+// samples (assumed already Hann-windowed by the caller is not required;
+// goertzel applies its own Hann taper internally).
+func goertzel(samples []float64, freq float64, rate uint) float64 {
+	n := len(samples)
+	if n == 0 {
+		return 0
+	}
+
+	w := 2 * math.Pi * freq / float64(rate)
+	coeff := 2 * math.Cos(w)
+
+	var s0, s1, s2 float64
+	for i, x := range samples {
+		taper := 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(n-1))
+		s0 = x*taper + coeff*s1 - s2
+		s2 = s1
+		s1 = s0
+	}
+
+	real := s1 - s2*math.Cos(w)
+	imag := s2 * math.Sin(w)
+	return math.Hypot(real, imag) / float64(n)
+}
+
+// cqtSurface computes the constant-Q magnitude surface of samples
+// (mono, at rate Hz) as [frame][bin]. Each bin uses its own window
+// length, centered on the frame's hop position, via a per-bin Goertzel
+// filter rather than a shared FFT, which keeps the implementation simple
+// at the cost of being O(bins x frames x window).
+func cqtSurface(samples []float64, rate uint) ([][]float64, []float64) {
+	bins := cqtBins()
+	if len(samples) < minWindow {
+		return nil, bins
+	}
+
+	numFrames := (len(samples)-minWindow)/hopSize + 1
+	surface := make([][]float64, numFrames)
+
+	for f := 0; f < numFrames; f++ {
+		center := f*hopSize + minWindow/2
+		row := make([]float64, len(bins))
+		for b, freq := range bins {
+			win := windowFor(freq, rate)
+			start := center - win/2
+			end := start + win
+			if start < 0 {
+				start = 0
+			}
+			if end > len(samples) {
+				end = len(samples)
+			}
+			if start >= end {
+				continue
+			}
+			row[b] = goertzel(samples[start:end], freq, rate)
+		}
+		surface[f] = row
+	}
+	return surface, bins
+}
@@ -0,0 +1,17 @@
+// Package landmark implements a Panako-style landmark fingerprinter: an
+// alternative to the chromaprint-family algorithms (see the chromaprintgo
+// subpackage) that is robust to pitch-shifting and time-stretching, at
+// the cost of being more expensive to compute and match.
+//
+// Audio is transformed with a constant-Q transform so that a pitch shift
+// becomes a simple translation along the frequency axis. Local energy
+// peaks ("events") are picked out of the resulting time-frequency
+// surface, and every nearby pair of events is combined with a third,
+// intermediate event into a hash built from the ratios between their
+// frequencies and the ratios between their time gaps. Because the hash
+// is built from ratios rather than absolute frequencies or absolute
+// timestamps, it is unchanged by a constant pitch shift or a constant
+// tempo change, which lets MatchLandmarks recognize a sped-up or
+// pitch-shifted copy of a recording that a chromaprint-style fingerprint
+// would not.
+package landmark
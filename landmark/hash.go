@@ -0,0 +1,108 @@
+package landmark
+
+import "math"
+
+const (
+	// targetFrames bounds how far ahead of an anchor event the other
+	// two points of a triple may be, in frames.
+	targetFrames = 200
+	// fanout caps how many of the nearest events within targetFrames
+	// of an anchor are combined into triples, bounding the O(n^2)
+	// blow-up of all-pairs triple formation.
+	fanout = 4
+	// ratioBits is the number of bits each of the three invariant
+	// ratios is quantized to before being packed into a hash.
+	ratioBits = 16
+)
+
+// LandmarkHash is a single fingerprint hash: a 64-bit value built from
+// the frequency and time ratios of three nearby events, together with
+// the time at which the anchor event (the earliest of the three)
+// occurred. Because Hash is built purely from ratios, it is unchanged
+// by a constant pitch shift or tempo change applied to the source
+// audio. MatchLandmarks divides a reference hash's Time by the matching
+// query hash's Time to get that pair's time factor; it does not estimate
+// a time offset between query and reference.
+type LandmarkHash struct {
+	Hash uint64
+	Time float64
+	// AnchorFreq is the absolute frequency, in Hz, of the triple's
+	// anchor event. It plays no part in Hash itself -- that is what
+	// keeps Hash invariant to pitch shift -- but MatchLandmarks uses
+	// it to estimate how much of a pitch shift relates two recordings
+	// once their hashes have been matched.
+	AnchorFreq float64
+}
+
+// buildHashes forms every anchor/p1/p2 triple within targetFrames of
+// each other (capped to fanout candidates per anchor) and turns each
+// into a LandmarkHash. hopSeconds converts a frame index into a
+// timestamp in seconds.
+func buildHashes(events []event, hopSeconds float64) []LandmarkHash {
+	var hashes []LandmarkHash
+	for i, anchor := range events {
+		var candidates []event
+		for j := i + 1; j < len(events) && len(candidates) < fanout*4; j++ {
+			if events[j].frame-anchor.frame > targetFrames {
+				break
+			}
+			candidates = append(candidates, events[j])
+		}
+		if len(candidates) > fanout {
+			candidates = candidates[:fanout]
+		}
+
+		for a := 0; a < len(candidates); a++ {
+			for b := a + 1; b < len(candidates); b++ {
+				p1, p2 := candidates[a], candidates[b]
+				if p1.frame == anchor.frame || p2.frame == p1.frame {
+					continue
+				}
+				hashes = append(hashes, LandmarkHash{
+					Hash:       tripleHash(anchor, p1, p2),
+					Time:       float64(anchor.frame) * hopSeconds,
+					AnchorFreq: anchor.freq,
+				})
+			}
+		}
+	}
+	return hashes
+}
+
+// tripleHash packs the frequency ratios of p1 and p2 relative to anchor,
+// and the time ratio between the two gaps, into a 48-bit value held in a
+// uint64. Using ratios rather than absolute frequencies or absolute
+// frame numbers makes the hash invariant to a constant pitch shift or
+// time stretch applied uniformly to the recording.
+func tripleHash(anchor, p1, p2 event) uint64 {
+	freqRatio1 := quantizeRatio(p1.freq / anchor.freq)
+	freqRatio2 := quantizeRatio(p2.freq / anchor.freq)
+	timeRatio := quantizeRatio(float64(p2.frame-anchor.frame) / float64(p1.frame-anchor.frame))
+
+	return freqRatio1<<(2*ratioBits) | freqRatio2<<ratioBits | timeRatio
+}
+
+// quantizeRatio buckets a positive ratio, expected to be roughly in
+// [0.1, 10], into a ratioBits-wide value via a log scale, so that
+// equally perceptible ratios (e.g. a semitone either way) land in
+// similarly sized buckets regardless of magnitude.
+func quantizeRatio(r float64) uint64 {
+	if r <= 0 {
+		r = 1e-6
+	}
+	const (
+		logMin = -4.0 // log2(1/16)
+		logMax = 4.0  // log2(16)
+	)
+	l := math.Log2(r)
+	if l < logMin {
+		l = logMin
+	}
+	if l > logMax {
+		l = logMax
+	}
+
+	buckets := float64(uint64(1) << ratioBits)
+	q := (l - logMin) / (logMax - logMin) * (buckets - 1)
+	return uint64(q + 0.5)
+}
@@ -0,0 +1,47 @@
+package landmark
+
+import "github.com/go-fingerprint/fingerprint"
+
+// LandmarkCalculator is the landmark-fingerprint equivalent of
+// fingerprint.Calculator: it computes a set of pitch- and
+// tempo-invariant LandmarkHash values from raw audio, rather than a
+// single chromaprint-style []int32 fingerprint.
+type LandmarkCalculator interface {
+	Landmarks(i fingerprint.RawInfo) ([]LandmarkHash, error)
+}
+
+// Calculator is the package's LandmarkCalculator implementation: a
+// constant-Q, spectral-peak-triple fingerprinter in the style of
+// Panako.
+type Calculator struct{}
+
+// New returns a Calculator.
+func New() *Calculator {
+	return &Calculator{}
+}
+
+// Landmarks reads and decodes the PCM audio described by i, computes its
+// constant-Q surface, picks local-maximum events from it, and returns
+// the LandmarkHash triples formed from nearby events.
+func (c *Calculator) Landmarks(i fingerprint.RawInfo) ([]LandmarkHash, error) {
+	samples, err := readPCM(i.Src, i.Channels)
+	if err != nil {
+		return nil, err
+	}
+
+	if i.MaxSeconds > 0 {
+		maxSamples := int(i.MaxSeconds * i.Rate)
+		if maxSamples < len(samples) {
+			samples = samples[:maxSamples]
+		}
+	}
+
+	surface, bins := cqtSurface(samples, i.Rate)
+	if surface == nil {
+		return nil, nil
+	}
+
+	events := pickPeaks(surface, bins)
+	hopSeconds := float64(hopSize) / float64(i.Rate)
+	return buildHashes(events, hopSeconds), nil
+}
@@ -0,0 +1,103 @@
+package landmark
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrNoMatch describes an error that occurs when MatchLandmarks cannot
+// find any hash shared between query and ref.
+var ErrNoMatch = errors.New("landmark: no matching hashes found")
+
+// factorBucket is the width used to bucket candidate time- and
+// frequency-stretch factors before voting, so that near-identical
+// estimates from different matching hashes reinforce the same bucket
+// rather than being spread across many.
+const factorBucket = 0.01
+
+// MatchLandmarks compares query against ref by first finding every pair
+// of hashes with an identical Hash value, then verifying the match with
+// a Hough-like vote: each matching pair implies a candidate (time
+// stretch factor, pitch shift factor) pair, and candidates are bucketed
+// so that a real alignment -- where many matching hashes agree on the
+// same factors -- stands out from coincidental hash collisions, which
+// scatter across many different factors. A pair's time factor is the
+// ratio of the two anchors' absolute Times and its frequency factor the
+// ratio of their AnchorFreqs. No time offset is estimated, so the time
+// factor measures a tempo change only between recordings that start at
+// the same point; the votes of a query that begins partway into ref are
+// spread across many time factors. It returns, as score, the
+// fraction of query hashes that voted for the winning bucket (0 to 1,
+// counting each query hash at most once even if it collided with
+// several ref hashes in that bucket), along with the bucket's time and
+// frequency factors (ref relative to query).
+func MatchLandmarks(query, ref []LandmarkHash) (score float64, timeFactor, freqFactor float64, err error) {
+	if len(query) == 0 || len(ref) == 0 {
+		return 0, 0, 0, ErrNoMatch
+	}
+
+	byHash := make(map[uint64][]LandmarkHash, len(ref))
+	for _, h := range ref {
+		byHash[h.Hash] = append(byHash[h.Hash], h)
+	}
+
+	type bucketKey struct{ t, f int64 }
+	voters := make(map[bucketKey]map[int]struct{})
+	sums := make(map[bucketKey][2]float64)
+	pairs := make(map[bucketKey]int)
+
+	matched := 0
+	for qi, q := range query {
+		hits, ok := byHash[q.Hash]
+		if !ok {
+			continue
+		}
+		matched++
+		for _, r := range hits {
+			if q.Time <= 0 || q.AnchorFreq <= 0 {
+				continue
+			}
+
+			t := r.Time / q.Time
+			f := r.AnchorFreq / q.AnchorFreq
+			if t <= 0 || f <= 0 || math.IsInf(t, 0) || math.IsInf(f, 0) {
+				continue
+			}
+
+			key := bucketKey{
+				t: int64(math.Round(t / factorBucket)),
+				f: int64(math.Round(f / factorBucket)),
+			}
+			if voters[key] == nil {
+				voters[key] = make(map[int]struct{})
+			}
+			voters[key][qi] = struct{}{}
+			pairs[key]++
+			s := sums[key]
+			sums[key] = [2]float64{s[0] + t, s[1] + f}
+		}
+	}
+
+	if matched == 0 {
+		return 0, 0, 0, ErrNoMatch
+	}
+
+	var bestKey bucketKey
+	bestVoters := 0
+	for k, v := range voters {
+		if len(v) > bestVoters {
+			bestVoters = len(v)
+			bestKey = k
+		}
+	}
+	if bestVoters == 0 {
+		return 0, 0, 0, ErrNoMatch
+	}
+
+	s := sums[bestKey]
+	n := float64(pairs[bestKey])
+	timeFactor = s[0] / n
+	freqFactor = s[1] / n
+	score = float64(bestVoters) / float64(len(query))
+	return score, timeFactor, freqFactor, nil
+}
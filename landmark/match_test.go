@@ -0,0 +1,59 @@
+package landmark
+
+import "testing"
+
+func TestMatchLandmarksSelfMatch(t *testing.T) {
+	hashes := []LandmarkHash{
+		{Hash: 1, Time: 1.0, AnchorFreq: 440},
+		{Hash: 2, Time: 2.0, AnchorFreq: 880},
+		{Hash: 3, Time: 3.0, AnchorFreq: 220},
+	}
+
+	score, timeFactor, freqFactor, err := MatchLandmarks(hashes, hashes)
+	if err != nil {
+		t.Fatalf("MatchLandmarks: %v", err)
+	}
+	if score != 1.0 {
+		t.Fatalf("score = %f, want 1.0 for a self-match", score)
+	}
+	if timeFactor != 1.0 || freqFactor != 1.0 {
+		t.Fatalf("timeFactor = %f, freqFactor = %f, want 1.0, 1.0", timeFactor, freqFactor)
+	}
+}
+
+func TestMatchLandmarksScoreNeverExceedsOne(t *testing.T) {
+	query := []LandmarkHash{{Hash: 1, Time: 1.0, AnchorFreq: 100}}
+	// Several ref hashes collide with the single query hash and land in
+	// the same (time, freq) factor bucket: that must count as one vote
+	// toward score, not one per colliding ref hash.
+	ref := []LandmarkHash{
+		{Hash: 1, Time: 2.0, AnchorFreq: 200},
+		{Hash: 1, Time: 2.0, AnchorFreq: 200},
+		{Hash: 1, Time: 2.0, AnchorFreq: 200},
+		{Hash: 1, Time: 2.0, AnchorFreq: 200},
+		{Hash: 1, Time: 2.0, AnchorFreq: 200},
+	}
+
+	score, timeFactor, freqFactor, err := MatchLandmarks(query, ref)
+	if err != nil {
+		t.Fatalf("MatchLandmarks: %v", err)
+	}
+	if score > 1.0 {
+		t.Fatalf("score = %f, want <= 1.0", score)
+	}
+	if score != 1.0 {
+		t.Fatalf("score = %f, want 1.0 (the one query hash matched)", score)
+	}
+	if timeFactor != 2.0 || freqFactor != 2.0 {
+		t.Fatalf("timeFactor = %f, freqFactor = %f, want 2.0, 2.0", timeFactor, freqFactor)
+	}
+}
+
+func TestMatchLandmarksNoOverlap(t *testing.T) {
+	query := []LandmarkHash{{Hash: 1, Time: 1.0, AnchorFreq: 100}}
+	ref := []LandmarkHash{{Hash: 2, Time: 1.0, AnchorFreq: 100}}
+
+	if _, _, _, err := MatchLandmarks(query, ref); err != ErrNoMatch {
+		t.Fatalf("err = %v, want ErrNoMatch", err)
+	}
+}
@@ -0,0 +1,77 @@
+package landmark
+
+const (
+	// freqNeighborhood and timeNeighborhood set the size, in bins and
+	// frames respectively, of the local region a point must dominate
+	// to be kept as an event.
+	freqNeighborhood = 100
+	timeNeighborhood = 25
+)
+
+// event is a single local-maximum point picked out of the CQT surface.
+type event struct {
+	frame int
+	bin   int
+	freq  float64
+	mag   float64
+}
+
+// pickPeaks returns every point in surface that is a local maximum over
+// both a frequency-neighborhood window (freqNeighborhood bins wide,
+// centered on the point) and a time-neighborhood window
+// (timeNeighborhood frames wide, centered on the point).
+func pickPeaks(surface [][]float64, bins []float64) []event {
+	var events []event
+	for f, row := range surface {
+		for b, mag := range row {
+			if mag <= 0 {
+				continue
+			}
+			if !isFreqMax(row, b, mag) {
+				continue
+			}
+			if !isTimeMax(surface, f, b, mag) {
+				continue
+			}
+			events = append(events, event{frame: f, bin: b, freq: bins[b], mag: mag})
+		}
+	}
+	return events
+}
+
+func isFreqMax(row []float64, b int, mag float64) bool {
+	lo := b - freqNeighborhood/2
+	hi := b + freqNeighborhood/2
+	if lo < 0 {
+		lo = 0
+	}
+	if hi >= len(row) {
+		hi = len(row) - 1
+	}
+	for i := lo; i <= hi; i++ {
+		if i != b && row[i] > mag {
+			return false
+		}
+	}
+	return true
+}
+
+func isTimeMax(surface [][]float64, f, b int, mag float64) bool {
+	lo := f - timeNeighborhood/2
+	hi := f + timeNeighborhood/2
+	if lo < 0 {
+		lo = 0
+	}
+	if hi >= len(surface) {
+		hi = len(surface) - 1
+	}
+	for i := lo; i <= hi; i++ {
+		if i == f {
+			continue
+		}
+		if b < len(surface[i]) && surface[i][b] > mag {
+			return false
+		}
+	}
+	return true
+}